@@ -8,17 +8,18 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/expr-lang/expr/vm"
 	"github.com/oracle/oci-go-sdk/v65/common"
 	"github.com/oracle/oci-go-sdk/v65/cloudguard"
+	"github.com/oracle/oci-go-sdk/v65/identity"
 )
 
 type ActivityFilter struct {
@@ -30,6 +31,12 @@ type ActivityFilter struct {
 	ProblemID     string
 	RiskLevel     string
 	Limit         int
+
+	// Expr is the raw -filter expression (or the one derived from the
+	// legacy flags above), and CompiledFilter its compiled form. A nil
+	// CompiledFilter matches every problem.
+	Expr           string
+	CompiledFilter *vm.Program
 }
 
 type DetectedProblem struct {
@@ -65,9 +72,15 @@ type ActivitySummary struct {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
+
 	// Define command line flags
 	compartmentID := flag.String("compartment-id", "", "Compartment ID (required)")
-	outputFile := flag.String("output", "cloudguard_activity.csv", "Output CSV file")
+	outputFile := flag.String("output", "cloudguard_activity.csv", "Output file (\"-\" for stdout)")
+	format := flag.String("format", "csv", "Output format: csv|json|jsonl|table|raw")
 	daysBack := flag.Int("days", 7, "Number of days back to search")
 	region := flag.String("region", "", "Specific region to filter")
 	resourceType := flag.String("resource-type", "", "Resource type filter")
@@ -75,10 +88,21 @@ func main() {
 	riskLevel := flag.String("risk-level", "", "Risk level filter (CRITICAL, HIGH, MEDIUM, LOW)")
 	limit := flag.Int("limit", 1000, "Maximum number of results")
 	summaryOnly := flag.Bool("summary", false, "Print summary only (no CSV export)")
+	regions := flag.String("regions", "", "Comma-separated regions to scan concurrently (e.g. us-ashburn-1,eu-frankfurt-1), or \"all\" for every region the tenancy is subscribed to")
+	compartments := flag.String("compartments", "", "Comma-separated compartment OCIDs to scan concurrently (overrides -compartment-id)")
+	recursiveCompartments := flag.Bool("recursive-compartments", false, "Recursively include each compartment's subtree via the Identity SDK")
+	workers := flag.Int("workers", 4, "Concurrent worker count for -regions/-compartments scans")
+	notifiersConfig := flag.String("notifiers-config", "", "Path to a notifiers.yaml mapping filters to Slack/webhook/email/PagerDuty notifiers")
+	notifyStateFile := flag.String("notify-state-file", ".cloudguard-notify-state.json", "File tracking previously-notified problem IDs, to avoid re-alerting on unchanged problems")
+	filterExpr := flag.String("filter", "", `expr filter, e.g. 'risk_level in ["HIGH","CRITICAL"] and days_since_detection > 3'; overrides -risk-level/-resource-type/-region/-problem-id when set`)
+	stateDB := flag.String("state-db", "", "Path to a SQLite database recording problem history across runs; enables diff reporting when set")
+	longStandingDays := flag.Int("long-standing-days", 30, "Problems open more than this many days are flagged as long-standing in the diff report")
 	flag.Parse()
 
+	multiScan := *regions != "" || *compartments != ""
+
 	// Validate required flags
-	if *compartmentID == "" {
+	if !multiScan && *compartmentID == "" {
 		*compartmentID = os.Getenv("OCI_COMPARTMENT_ID")
 		if *compartmentID == "" {
 			fmt.Println("Error: compartment-id is required")
@@ -87,13 +111,6 @@ func main() {
 		}
 	}
 
-	// Create Cloud Guard client
-	client, err := cloudguard.NewCloudGuardClientWithConfigurationProvider(common.DefaultConfigProvider())
-	if err != nil {
-		fmt.Printf("Error creating Cloud Guard client: %v\n", err)
-		os.Exit(1)
-	}
-
 	// Set up time range
 	endTime := time.Now()
 	startTime := endTime.AddDate(0, 0, -*daysBack)
@@ -109,14 +126,21 @@ func main() {
 		Limit:         *limit,
 	}
 
-	fmt.Printf("Searching Cloud Guard activity from %s to %s\n", 
-		startTime.Format("2006-01-02"), 
-		endTime.Format("2006-01-02"))
-	fmt.Printf("Compartment: %s\n", *compartmentID)
-	
-	if *region != "" {
-		fmt.Printf("Region: %s\n", *region)
+	filter.Expr = *filterExpr
+	if filter.Expr == "" {
+		filter.Expr = legacyFilterExpression(filter)
+	}
+	compiledFilter, err := compileFilterExpression(filter.Expr)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
 	}
+	filter.CompiledFilter = compiledFilter
+
+	fmt.Printf("Searching Cloud Guard activity from %s to %s\n",
+		startTime.Format("2006-01-02"),
+		endTime.Format("2006-01-02"))
+
 	if *resourceType != "" {
 		fmt.Printf("Resource Type: %s\n", *resourceType)
 	}
@@ -124,8 +148,61 @@ func main() {
 		fmt.Printf("Risk Level: %s\n", *riskLevel)
 	}
 
-	// Get detected problems
-	problems, err := getDetectedProblems(client, filter)
+	var problems []DetectedProblem
+
+	if multiScan {
+		scanCompartments := *compartmentID
+		var compartmentList []string
+		if *compartments != "" {
+			compartmentList = strings.Split(*compartments, ",")
+		} else if scanCompartments != "" {
+			compartmentList = []string{scanCompartments}
+		} else {
+			fmt.Println("Error: -compartments or -compartment-id is required for a multi-region/compartment scan")
+			os.Exit(1)
+		}
+
+		var regionList []string
+		if *regions != "" {
+			regionList = strings.Split(*regions, ",")
+		} else if *region != "" {
+			regionList = []string{*region}
+		} else {
+			fmt.Println("Error: -regions or -region is required for a multi-region/compartment scan")
+			os.Exit(1)
+		}
+
+		fmt.Printf("Regions: %s\n", strings.Join(regionList, ", "))
+		fmt.Printf("Compartments: %s\n", strings.Join(compartmentList, ", "))
+
+		identityClient, identityErr := identity.NewIdentityClientWithConfigurationProvider(common.DefaultConfigProvider())
+		if identityErr != nil {
+			fmt.Printf("Error creating Identity client: %v\n", identityErr)
+			os.Exit(1)
+		}
+
+		problems, err = runScan(context.Background(), identityClient, filter, ScanConfig{
+			Regions:          regionList,
+			Compartments:     compartmentList,
+			RecursiveSubtree: *recursiveCompartments,
+			Workers:          *workers,
+		})
+	} else {
+		fmt.Printf("Compartment: %s\n", *compartmentID)
+		if *region != "" {
+			fmt.Printf("Region: %s\n", *region)
+		}
+
+		// Create Cloud Guard client
+		client, clientErr := cloudguard.NewCloudGuardClientWithConfigurationProvider(common.DefaultConfigProvider())
+		if clientErr != nil {
+			fmt.Printf("Error creating Cloud Guard client: %v\n", clientErr)
+			os.Exit(1)
+		}
+
+		problems, err = getDetectedProblems(client, filter)
+	}
+
 	if err != nil {
 		fmt.Printf("Error getting detected problems: %v\n", err)
 		os.Exit(1)
@@ -135,22 +212,95 @@ func main() {
 	summary := generateSummary(problems)
 	printDetailedSummary(summary)
 
+	if *notifiersConfig != "" {
+		if err := runNotifiers(context.Background(), *notifiersConfig, *notifyStateFile, problems); err != nil {
+			fmt.Printf("Error running notifiers: %v\n", err)
+		}
+	}
+
+	if *stateDB != "" {
+		if err := recordHistoryAndDiff(*stateDB, *longStandingDays, problems); err != nil {
+			fmt.Printf("Error recording scan history: %v\n", err)
+		}
+	}
+
 	if *summaryOnly {
-		fmt.Println("\nSummary only mode - skipping CSV export")
+		fmt.Println("\nSummary only mode - skipping export")
 		return
 	}
 
-	// Export to CSV
-	err = exportToCSV(problems, *outputFile)
+	renderer, err := rendererForFormat(*format)
 	if err != nil {
-		fmt.Printf("Error exporting to CSV: %v\n", err)
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := renderToDestination(renderer, problems, summary, *outputFile); err != nil {
+		fmt.Printf("Error writing report: %v\n", err)
 		os.Exit(1)
 	}
 
+	if *outputFile == "-" {
+		return
+	}
 	fmt.Printf("\nActivity report saved to: %s\n", *outputFile)
 }
 
+// renderToDestination runs renderer over problems/summary and writes the
+// result to destination, which may be a file path or "-" for stdout.
+func renderToDestination(renderer Renderer, problems []DetectedProblem, summary ActivitySummary, destination string) error {
+	if destination == "-" {
+		return renderer.Render(os.Stdout, problems, summary)
+	}
+
+	dir := filepath.Dir(destination)
+	if dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %v", err)
+		}
+	}
+
+	file, err := os.Create(destination)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer file.Close()
+
+	return renderer.Render(file, problems, summary)
+}
+
+// progressTicker lets the page-fetch/enrich loop below report progress
+// without knowing whether it's driving an interactive bar or nothing at
+// all. Splitting it into addTotal/increment (rather than one combined
+// tick) mirrors exactly where cheggaaa/pb needs each call: total must grow
+// before the work it accounts for starts, and increment fires once that
+// work is done.
+type progressTicker interface {
+	addTotal()
+	increment()
+}
+
+// noopTicker is the progressTicker used by callers that don't track
+// progress, so the shared fetch loop never has to nil-check.
+type noopTicker struct{}
+
+func (noopTicker) addTotal()  {}
+func (noopTicker) increment() {}
+
+// getDetectedProblems lists and enriches every problem matching filter in
+// a single compartment/region, with no progress reporting.
 func getDetectedProblems(client cloudguard.CloudGuardClient, filter ActivityFilter) ([]DetectedProblem, error) {
+	return fetchDetectedProblems(context.Background(), client, filter, noopTicker{})
+}
+
+// fetchDetectedProblems is the paginated list+enrich loop shared by the
+// single-scan path (getDetectedProblems) and the concurrent multi-target
+// path (getDetectedProblemsWithProgress in scan.go), so the two don't
+// drift out of sync as pagination or field conversion changes. progress
+// is ticked around the two units of real work (a page fetch, a problem
+// enrichment) so a caller driving a progress bar sees it grow alongside
+// work actually discovered.
+func fetchDetectedProblems(ctx context.Context, client cloudguard.CloudGuardClient, filter ActivityFilter, progress progressTicker) ([]DetectedProblem, error) {
 	var allProblems []DetectedProblem
 	var page *string
 
@@ -161,25 +311,27 @@ func getDetectedProblems(client cloudguard.CloudGuardClient, filter ActivityFilt
 			Limit:         common.Int(filter.Limit),
 		}
 
-		response, err := client.ListProblems(context.Background(), request)
+		progress.addTotal()
+		response, err := client.ListProblems(ctx, request)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list problems: %v", err)
 		}
+		progress.increment()
 
 		// Process each problem using correct field names
 		for _, problem := range response.Items {
 			detectedProblem := DetectedProblem{
-				ID:              safeString(problem.Id),
-				ResourceID:      safeString(problem.ResourceId),
-				ResourceName:    safeString(problem.ResourceName),
-				ResourceType:    safeString(problem.ResourceType),
-				Region:          safeString(problem.Region),
-				CompartmentID:   safeString(problem.CompartmentId),
-				RiskLevel:       string(problem.RiskLevel),
-				DetectorRuleID:  safeString(problem.DetectorRuleId),
-				Labels:          problem.Labels,
-				TargetID:        safeString(problem.TargetId),
-				LifecycleState:  string(problem.LifecycleState),
+				ID:             safeString(problem.Id),
+				ResourceID:     safeString(problem.ResourceId),
+				ResourceName:   safeString(problem.ResourceName),
+				ResourceType:   safeString(problem.ResourceType),
+				Region:         safeString(problem.Region),
+				CompartmentID:  safeString(problem.CompartmentId),
+				RiskLevel:      string(problem.RiskLevel),
+				DetectorRuleID: safeString(problem.DetectorRuleId),
+				Labels:         problem.Labels,
+				TargetID:       safeString(problem.TargetId),
+				LifecycleState: string(problem.LifecycleState),
 			}
 
 			// Get detector from detector rule ID or use a default
@@ -202,7 +354,9 @@ func getDetectedProblems(client cloudguard.CloudGuardClient, filter ActivityFilt
 			// Apply manual filtering if filter parameters were provided
 			if shouldIncludeProblem(detectedProblem, filter) {
 				// Get additional details for the problem
+				progress.addTotal()
 				enrichedProblem, err := enrichProblemDetails(client, detectedProblem)
+				progress.increment()
 				if err != nil {
 					fmt.Printf("Warning: Failed to enrich problem %s: %v\n", detectedProblem.ID, err)
 					allProblems = append(allProblems, detectedProblem)
@@ -237,26 +391,20 @@ func extractDetectorFromRuleID(ruleID string) string {
 }
 
 func shouldIncludeProblem(problem DetectedProblem, filter ActivityFilter) bool {
-	// Manual filtering since Filter field might not be available in request
-	if filter.Region != "" && problem.Region != filter.Region {
-		return false
-	}
-	if filter.ResourceType != "" && problem.ResourceType != filter.ResourceType {
-		return false
-	}
-	if filter.ProblemID != "" && problem.ID != filter.ProblemID {
-		return false
-	}
-	if filter.RiskLevel != "" && problem.RiskLevel != filter.RiskLevel {
-		return false
-	}
 	if !filter.StartTime.IsZero() && problem.LastDetected.Before(filter.StartTime) {
 		return false
 	}
 	if !filter.EndTime.IsZero() && problem.LastDetected.After(filter.EndTime) {
 		return false
 	}
-	return true
+
+	included, err := evaluateFilter(filter.CompiledFilter, problem)
+	if err != nil {
+		fmt.Printf("Warning: filter evaluation failed for problem %s: %v\n", problem.ID, err)
+		return true
+	}
+
+	return included
 }
 
 func enrichProblemDetails(client cloudguard.CloudGuardClient, problem DetectedProblem) (DetectedProblem, error) {
@@ -304,41 +452,45 @@ func generateSummary(problems []DetectedProblem) ActivitySummary {
 }
 
 func printDetailedSummary(summary ActivitySummary) {
-	fmt.Printf("\n=== CLOUD GUARD ACTIVITY SUMMARY ===\n")
-	fmt.Printf("Total problems detected: %d\n", summary.TotalProblems)
+	printDetailedSummaryTo(os.Stdout, summary)
+}
+
+func printDetailedSummaryTo(w io.Writer, summary ActivitySummary) {
+	fmt.Fprintf(w, "\n=== CLOUD GUARD ACTIVITY SUMMARY ===\n")
+	fmt.Fprintf(w, "Total problems detected: %d\n", summary.TotalProblems)
 
 	if summary.TotalProblems == 0 {
 		return
 	}
 
-	fmt.Printf("\nBy Risk Level:\n")
+	fmt.Fprintf(w, "\nBy Risk Level:\n")
 	for level, count := range summary.ByRiskLevel {
 		percentage := float64(count) / float64(summary.TotalProblems) * 100
-		fmt.Printf("  %-10s: %3d (%5.1f%%)\n", level, count, percentage)
+		fmt.Fprintf(w, "  %-10s: %3d (%5.1f%%)\n", level, count, percentage)
 	}
 
-	fmt.Printf("\nBy Resource Type:\n")
+	fmt.Fprintf(w, "\nBy Resource Type:\n")
 	for resourceType, count := range summary.ByResourceType {
-		fmt.Printf("  %-25s: %3d\n", resourceType, count)
+		fmt.Fprintf(w, "  %-25s: %3d\n", resourceType, count)
 	}
 
-	fmt.Printf("\nBy Detector:\n")
+	fmt.Fprintf(w, "\nBy Detector:\n")
 	for detector, count := range summary.ByDetector {
-		fmt.Printf("  %-20s: %3d\n", detector, count)
+		fmt.Fprintf(w, "  %-20s: %3d\n", detector, count)
 	}
 
-	fmt.Printf("\nBy Region:\n")
+	fmt.Fprintf(w, "\nBy Region:\n")
 	for region, count := range summary.ByRegion {
-		fmt.Printf("  %-20s: %3d\n", region, count)
+		fmt.Fprintf(w, "  %-20s: %3d\n", region, count)
 	}
 
-	fmt.Printf("\nMost Recent Problems:\n")
+	fmt.Fprintf(w, "\nMost Recent Problems:\n")
 	for i, problem := range summary.RecentProblems {
 		desc := problem.Description
 		if desc == "N/A" {
 			desc = problem.ResourceType + " issue"
 		}
-		fmt.Printf("  %d. [%s] %s - %s (%s) - %d days ago\n",
+		fmt.Fprintf(w, "  %d. [%s] %s - %s (%s) - %d days ago\n",
 			i+1,
 			problem.LastDetected.Format("01/02 15:04"),
 			problem.ResourceType,
@@ -348,85 +500,19 @@ func printDetailedSummary(summary ActivitySummary) {
 	}
 }
 
-func exportToCSV(problems []DetectedProblem, filename string) error {
-	// Create directory if it doesn't exist
-	dir := filepath.Dir(filename)
-	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory: %v", err)
-		}
-	}
-
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %v", err)
-	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// Write headers
-	headers := []string{
-		"Problem_ID",
-		"First_Detected",
-		"Last_Detected",
-		"Days_Since_Detection", // New column
-		"Resource_ID",
-		"Resource_Name",
-		"Resource_Type",
-		"Region",
-		"Compartment_ID",
-		"Detector",
-		"Risk_Level",
-		"Description",
-		"Recommendation",
-		"Detector_Rule_ID",
-		"Target_ID",
-		"Labels",
-		"Lifecycle_State",
-	}
-
-	if err := writer.Write(headers); err != nil {
-		return err
-	}
-
-	// Write data
-	for _, problem := range problems {
-		labels := strings.Join(problem.Labels, "|")
-		if labels == "" {
-			labels = "N/A"
-		}
-
-		row := []string{
-			problem.ID,
-			problem.FirstDetected.Format(time.RFC3339),
-			problem.LastDetected.Format(time.RFC3339),
-			strconv.Itoa(problem.DaysSinceDetection), // New field
-			problem.ResourceID,
-			problem.ResourceName,
-			problem.ResourceType,
-			problem.Region,
-			problem.CompartmentID,
-			problem.Detector,
-			problem.RiskLevel,
-			problem.Description,
-			problem.Recommendation,
-			problem.DetectorRuleID,
-			problem.TargetID,
-			labels,
-			problem.LifecycleState,
-		}
-
-		if err := writer.Write(row); err != nil {
-			return err
+// Helper functions
+func splitAndTrim(s string) []string {
+	parts := strings.Split(s, ",")
+	trimmed := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			trimmed = append(trimmed, part)
 		}
 	}
-
-	return nil
+	return trimmed
 }
 
-// Helper functions
 func safeString(s *string) string {
 	if s == nil {
 		return "N/A"
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// compileFilterExpression compiles a -filter expression against the
+// environment shape produced by filterEnv, so it can be evaluated
+// repeatedly per problem without recompiling.
+func compileFilterExpression(filterExpr string) (*vm.Program, error) {
+	if filterExpr == "" {
+		return nil, nil
+	}
+
+	program, err := expr.Compile(filterExpr, expr.Env(filterEnv(DetectedProblem{})), expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile filter expression %q: %v", filterExpr, err)
+	}
+
+	return program, nil
+}
+
+// legacyFilterExpression translates the flat -risk-level/-resource-type/
+// -region/-problem-id flags into an equivalent expr string, so they keep
+// working as syntactic sugar over the filter engine rather than a second
+// filtering code path.
+func legacyFilterExpression(filter ActivityFilter) string {
+	var clauses []string
+
+	if filter.RiskLevel != "" {
+		clauses = append(clauses, fmt.Sprintf("risk_level == %q", filter.RiskLevel))
+	}
+	if filter.ResourceType != "" {
+		clauses = append(clauses, fmt.Sprintf("resource_type == %q", filter.ResourceType))
+	}
+	if filter.Region != "" {
+		clauses = append(clauses, fmt.Sprintf("Region == %q", filter.Region))
+	}
+	if filter.ProblemID != "" {
+		clauses = append(clauses, fmt.Sprintf("id == %q", filter.ProblemID))
+	}
+
+	if len(clauses) == 0 {
+		return ""
+	}
+
+	return strings.Join(clauses, " and ")
+}
+
+// filterEnv is the variable and helper-function environment -filter
+// expressions are evaluated against for a single problem.
+func filterEnv(problem DetectedProblem) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                   problem.ID,
+		"risk_level":           problem.RiskLevel,
+		"resource_type":        problem.ResourceType,
+		"resource_id":          problem.ResourceID,
+		"resource_name":        problem.ResourceName,
+		"days_since_detection": problem.DaysSinceDetection,
+		"lifecycle_state":      problem.LifecycleState,
+		"labels":               problem.Labels,
+		"first_detected":       problem.FirstDetected,
+		"last_detected":        problem.LastDetected,
+		"Region":               problem.Region,
+		"Detector":             problem.Detector,
+		"Duration":             exprDuration,
+		"Now":                  time.Now,
+		"InCIDR":               exprInCIDR,
+		"LabelContains":        exprLabelContains,
+	}
+}
+
+// exprDuration backs the Duration("72h") helper; an unparsable duration
+// evaluates to zero rather than aborting the whole expression.
+func exprDuration(s string) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// exprInCIDR backs the InCIDR(ip, cidr) helper.
+func exprInCIDR(ip, cidr string) bool {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	return network.Contains(parsed)
+}
+
+// exprLabelContains backs the LabelContains(labels, "pci") helper.
+func exprLabelContains(labels []string, substr string) bool {
+	for _, label := range labels {
+		if strings.Contains(label, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateFilter runs the compiled -filter program (if any) against
+// problem. A nil program matches everything.
+func evaluateFilter(program *vm.Program, problem DetectedProblem) (bool, error) {
+	if program == nil {
+		return true, nil
+	}
+
+	output, err := expr.Run(program, filterEnv(problem))
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate filter expression: %v", err)
+	}
+
+	result, ok := output.(bool)
+	if !ok {
+		return false, fmt.Errorf("filter expression must evaluate to a boolean, got %T", output)
+	}
+
+	return result, nil
+}
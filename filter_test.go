@@ -0,0 +1,125 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLegacyFilterExpression(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ActivityFilter
+		want   string
+	}{
+		{name: "empty", filter: ActivityFilter{}, want: ""},
+		{
+			name:   "risk level only",
+			filter: ActivityFilter{RiskLevel: "HIGH"},
+			want:   `risk_level == "HIGH"`,
+		},
+		{
+			name:   "all flags combine with and",
+			filter: ActivityFilter{RiskLevel: "HIGH", ResourceType: "Instance", Region: "us-ashburn-1", ProblemID: "p1"},
+			want:   `risk_level == "HIGH" and resource_type == "Instance" and Region == "us-ashburn-1" and id == "p1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := legacyFilterExpression(tt.filter); got != tt.want {
+				t.Errorf("legacyFilterExpression() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateFilter(t *testing.T) {
+	problem := DetectedProblem{
+		ID:                 "p1",
+		RiskLevel:          "HIGH",
+		ResourceType:       "ObjectStorage",
+		Region:             "us-ashburn-1",
+		DaysSinceDetection: 5,
+		Labels:             []string{"pci-scope", "public-access"},
+	}
+
+	tests := []struct {
+		name   string
+		expr   string
+		expect bool
+	}{
+		{name: "nil program matches everything", expr: "", expect: true},
+		{name: "risk level in list", expr: `risk_level in ["HIGH","CRITICAL"]`, expect: true},
+		{name: "risk level not in list", expr: `risk_level in ["LOW","MEDIUM"]`, expect: false},
+		{name: "age threshold", expr: "days_since_detection > 3", expect: true},
+		{name: "age threshold not met", expr: "days_since_detection > 30", expect: false},
+		{name: "region regex", expr: `Region matches "^us-"`, expect: true},
+		{name: "label helper", expr: `LabelContains(labels, "public-access")`, expect: true},
+		{name: "label helper miss", expr: `LabelContains(labels, "nope")`, expect: false},
+		{name: "combined expression", expr: `risk_level == "HIGH" and days_since_detection > 3 and Region matches "^us-"`, expect: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := compileFilterExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("compileFilterExpression(%q) error: %v", tt.expr, err)
+			}
+
+			got, err := evaluateFilter(program, problem)
+			if err != nil {
+				t.Fatalf("evaluateFilter() error: %v", err)
+			}
+			if got != tt.expect {
+				t.Errorf("evaluateFilter(%q) = %v, want %v", tt.expr, got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestExprHelpers(t *testing.T) {
+	if got := exprDuration("72h"); got != 72*time.Hour {
+		t.Errorf("exprDuration(72h) = %v, want %v", got, 72*time.Hour)
+	}
+	if got := exprDuration("not-a-duration"); got != 0 {
+		t.Errorf("exprDuration(invalid) = %v, want 0", got)
+	}
+
+	if !exprInCIDR("10.0.0.5", "10.0.0.0/24") {
+		t.Error("exprInCIDR() = false, want true for address inside CIDR")
+	}
+	if exprInCIDR("192.168.1.1", "10.0.0.0/24") {
+		t.Error("exprInCIDR() = true, want false for address outside CIDR")
+	}
+	if exprInCIDR("not-an-ip", "10.0.0.0/24") {
+		t.Error("exprInCIDR() = true, want false for unparsable IP")
+	}
+
+	if !exprLabelContains([]string{"foo", "pci-scope"}, "pci") {
+		t.Error("exprLabelContains() = false, want true")
+	}
+	if exprLabelContains([]string{"foo"}, "pci") {
+		t.Error("exprLabelContains() = true, want false")
+	}
+}
+
+func TestCompileFilterExpressionInvalid(t *testing.T) {
+	if _, err := compileFilterExpression("risk_level =="); err == nil {
+		t.Error("compileFilterExpression() with malformed expression: want error, got nil")
+	}
+}
+
+func TestShouldIncludeProblemTimeWindow(t *testing.T) {
+	now := time.Now()
+	problem := DetectedProblem{ID: "p1", LastDetected: now}
+
+	filter := ActivityFilter{StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour)}
+	if shouldIncludeProblem(problem, filter) {
+		t.Error("shouldIncludeProblem() = true for a problem before the time window, want false")
+	}
+
+	filter = ActivityFilter{StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour)}
+	if !shouldIncludeProblem(problem, filter) {
+		t.Error("shouldIncludeProblem() = false for a problem inside the time window, want true")
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/eugsim1/cloudguard-activity-reporter/store"
+)
+
+func toStoreProblem(problem DetectedProblem) store.Problem {
+	return store.Problem{
+		ID:                 problem.ID,
+		ResourceName:       problem.ResourceName,
+		ResourceType:       problem.ResourceType,
+		Region:             problem.Region,
+		Detector:           problem.Detector,
+		RiskLevel:          problem.RiskLevel,
+		LifecycleState:     problem.LifecycleState,
+		DaysSinceDetection: problem.DaysSinceDetection,
+	}
+}
+
+// recordHistoryAndDiff opens the SQLite state db at dbPath, records the
+// current batch of problems against it, prints the resulting diff to
+// stdout, and saves the same diff as a timestamped CSV in the current
+// working directory.
+func recordHistoryAndDiff(dbPath string, longStandingDays int, problems []DetectedProblem) error {
+	st, err := store.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+
+	storeProblems := make([]store.Problem, 0, len(problems))
+	for _, problem := range problems {
+		storeProblems = append(storeProblems, toStoreProblem(problem))
+	}
+
+	report, err := st.RecordAndDiff(storeProblems, longStandingDays)
+	if err != nil {
+		return fmt.Errorf("failed to record scan history: %v", err)
+	}
+
+	report.PrintSummary(os.Stdout)
+
+	diffFile := fmt.Sprintf("cloudguard_diff_%s.csv", time.Now().Format("20060102_150405"))
+	file, err := os.Create(diffFile)
+	if err != nil {
+		return fmt.Errorf("failed to create diff report %s: %v", diffFile, err)
+	}
+	defer file.Close()
+
+	if err := report.WriteCSV(file); err != nil {
+		return fmt.Errorf("failed to write diff report %s: %v", diffFile, err)
+	}
+
+	fmt.Printf("Diff report saved to: %s\n", diffFile)
+	return nil
+}
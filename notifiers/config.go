@@ -0,0 +1,104 @@
+package notifiers
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the top-level shape of a -notifiers-config YAML file: a list
+// of rules, each pairing a Filter with the notifiers to fire when a
+// problem matches it.
+type Config struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule pairs a Filter with the notifier instances that should fire for any
+// problem it matches.
+type Rule struct {
+	Name      string           `yaml:"name"`
+	Filter    Filter           `yaml:"filter"`
+	Notifiers []NotifierConfig `yaml:"notifiers"`
+}
+
+// NotifierConfig is a tagged union of the notifier kinds this package
+// ships; exactly one of the type-specific fields should be set to match
+// Type.
+type NotifierConfig struct {
+	Type      string           `yaml:"type"`
+	Slack     *SlackConfig     `yaml:"slack,omitempty"`
+	Webhook   *WebhookConfig   `yaml:"webhook,omitempty"`
+	Email     *EmailConfig     `yaml:"email,omitempty"`
+	PagerDuty *PagerDutyConfig `yaml:"pagerduty,omitempty"`
+}
+
+// LoadConfig reads and parses a notifiers config file from path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notifiers config: %v", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse notifiers config: %v", err)
+	}
+
+	return &cfg, nil
+}
+
+// Build constructs the concrete Notifier for a NotifierConfig entry.
+func Build(cfg NotifierConfig) (Notifier, error) {
+	switch cfg.Type {
+	case "slack":
+		if cfg.Slack == nil {
+			return nil, fmt.Errorf("notifier type %q requires a slack config block", cfg.Type)
+		}
+		return NewSlackNotifier(*cfg.Slack), nil
+	case "webhook":
+		if cfg.Webhook == nil {
+			return nil, fmt.Errorf("notifier type %q requires a webhook config block", cfg.Type)
+		}
+		return NewWebhookNotifier(*cfg.Webhook), nil
+	case "email":
+		if cfg.Email == nil {
+			return nil, fmt.Errorf("notifier type %q requires an email config block", cfg.Type)
+		}
+		return NewEmailNotifier(*cfg.Email), nil
+	case "pagerduty":
+		if cfg.PagerDuty == nil {
+			return nil, fmt.Errorf("notifier type %q requires a pagerduty config block", cfg.Type)
+		}
+		return NewPagerDutyNotifier(*cfg.PagerDuty), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type %q", cfg.Type)
+	}
+}
+
+// BuildRules resolves every NotifierConfig referenced by cfg.Rules into a
+// live Notifier, returning rules paired with their ready-to-use notifiers.
+func BuildRules(cfg *Config) ([]ResolvedRule, error) {
+	resolved := make([]ResolvedRule, 0, len(cfg.Rules))
+
+	for _, rule := range cfg.Rules {
+		var built []Notifier
+		for _, notifierCfg := range rule.Notifiers {
+			notifier, err := Build(notifierCfg)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %v", rule.Name, err)
+			}
+			built = append(built, notifier)
+		}
+		resolved = append(resolved, ResolvedRule{Name: rule.Name, Filter: rule.Filter, Notifiers: built})
+	}
+
+	return resolved, nil
+}
+
+// ResolvedRule is a Rule with its notifier configs already built.
+type ResolvedRule struct {
+	Name      string
+	Filter    Filter
+	Notifiers []Notifier
+}
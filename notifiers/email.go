@@ -0,0 +1,56 @@
+package notifiers
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// EmailConfig configures an SMTP notifier.
+type EmailConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// EmailNotifier sends a single plaintext email summarizing a problem batch
+// over SMTP, authenticating with PLAIN auth when credentials are set.
+type EmailNotifier struct {
+	cfg EmailConfig
+}
+
+// NewEmailNotifier builds an EmailNotifier from cfg.
+func NewEmailNotifier(cfg EmailConfig) *EmailNotifier {
+	return &EmailNotifier{cfg: cfg}
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, problems []Problem) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Subject: Cloud Guard: %d new/escalated problem(s)\r\n", len(problems))
+	fmt.Fprintf(&body, "From: %s\r\nTo: %s\r\n\r\n", n.cfg.From, strings.Join(n.cfg.To, ", "))
+	for _, problem := range problems {
+		fmt.Fprintf(&body, "[%s] %s %s in %s (%s) - %d days old\r\n",
+			problem.RiskLevel, problem.ResourceType, problem.ResourceName, problem.Region, problem.Detector, problem.DaysSinceDetection)
+	}
+
+	addr := fmt.Sprintf("%s:%d", n.cfg.Host, n.cfg.Port)
+
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+
+	if err := smtp.SendMail(addr, auth, n.cfg.From, n.cfg.To, []byte(body.String())); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	return nil
+}
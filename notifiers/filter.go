@@ -0,0 +1,38 @@
+package notifiers
+
+import "strings"
+
+// Filter describes which problems a rule's notifiers should fire on. An
+// empty slice field matches every value for that dimension.
+type Filter struct {
+	RiskLevels    []string `yaml:"risk_level"`
+	ResourceTypes []string `yaml:"resource_type"`
+	Detectors     []string `yaml:"detector"`
+	MinAgeDays    int      `yaml:"min_age_days"`
+}
+
+// Matches reports whether problem satisfies every dimension of f.
+func (f Filter) Matches(problem Problem) bool {
+	if len(f.RiskLevels) > 0 && !containsFold(f.RiskLevels, problem.RiskLevel) {
+		return false
+	}
+	if len(f.ResourceTypes) > 0 && !containsFold(f.ResourceTypes, problem.ResourceType) {
+		return false
+	}
+	if len(f.Detectors) > 0 && !containsFold(f.Detectors, problem.Detector) {
+		return false
+	}
+	if problem.DaysSinceDetection < f.MinAgeDays {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, target) {
+			return true
+		}
+	}
+	return false
+}
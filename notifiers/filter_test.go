@@ -0,0 +1,53 @@
+package notifiers
+
+import "testing"
+
+func TestFilterMatches(t *testing.T) {
+	problem := Problem{
+		RiskLevel:          "HIGH",
+		ResourceType:       "ObjectStorage",
+		Detector:           "ConfigurationDetector",
+		DaysSinceDetection: 5,
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "empty filter matches everything", filter: Filter{}, want: true},
+		{name: "matching risk level", filter: Filter{RiskLevels: []string{"high", "critical"}}, want: true},
+		{name: "non-matching risk level", filter: Filter{RiskLevels: []string{"low"}}, want: false},
+		{name: "matching resource type", filter: Filter{ResourceTypes: []string{"ObjectStorage"}}, want: true},
+		{name: "non-matching resource type", filter: Filter{ResourceTypes: []string{"Instance"}}, want: false},
+		{name: "matching detector", filter: Filter{Detectors: []string{"configurationdetector"}}, want: true},
+		{name: "min age met", filter: Filter{MinAgeDays: 3}, want: true},
+		{name: "min age not met", filter: Filter{MinAgeDays: 10}, want: false},
+		{
+			name: "all dimensions match",
+			filter: Filter{
+				RiskLevels:    []string{"HIGH"},
+				ResourceTypes: []string{"ObjectStorage"},
+				Detectors:     []string{"ConfigurationDetector"},
+				MinAgeDays:    1,
+			},
+			want: true,
+		},
+		{
+			name: "one non-matching dimension fails the whole filter",
+			filter: Filter{
+				RiskLevels:    []string{"HIGH"},
+				ResourceTypes: []string{"Instance"},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(problem); got != tt.want {
+				t.Errorf("Filter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
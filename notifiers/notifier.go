@@ -0,0 +1,31 @@
+// Package notifiers implements pluggable alert destinations (Slack,
+// generic webhooks, email, PagerDuty) for newly detected or escalated
+// Cloud Guard problems. A deployment describes its rules in YAML, each a
+// Filter plus the destinations it fires; BuildRules turns that config into
+// ResolvedRules whose Notifiers the caller drives with Notify once per scan.
+package notifiers
+
+import "context"
+
+// Problem is the subset of a detected Cloud Guard problem a notifier needs
+// in order to render an alert. Callers convert their own scan result type
+// into Problem rather than this package depending on the scanner.
+type Problem struct {
+	ID                 string
+	ResourceName       string
+	ResourceType       string
+	Region             string
+	CompartmentID      string
+	Detector           string
+	RiskLevel          string
+	DaysSinceDetection int
+	Description        string
+	Recommendation     string
+	Labels             []string
+}
+
+// Notifier delivers an alert for a batch of problems to a single
+// destination (Slack channel, webhook, inbox, PagerDuty service, ...).
+type Notifier interface {
+	Notify(ctx context.Context, problems []Problem) error
+}
@@ -0,0 +1,87 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyConfig configures a PagerDuty Events v2 notifier.
+type PagerDutyConfig struct {
+	RoutingKey string `yaml:"routing_key"`
+	Severity   string `yaml:"severity,omitempty"`
+}
+
+// PagerDutyNotifier triggers one PagerDuty Events v2 alert per problem, so
+// each gets its own incident and dedup key.
+type PagerDutyNotifier struct {
+	cfg    PagerDutyConfig
+	client *http.Client
+}
+
+// NewPagerDutyNotifier builds a PagerDutyNotifier from cfg.
+func NewPagerDutyNotifier(cfg PagerDutyConfig) *PagerDutyNotifier {
+	return &PagerDutyNotifier{cfg: cfg, client: http.DefaultClient}
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string                `json:"routing_key"`
+	EventAction string                `json:"event_action"`
+	DedupKey    string                `json:"dedup_key"`
+	Payload     pagerDutyEventPayload `json:"payload"`
+}
+
+type pagerDutyEventPayload struct {
+	Summary       string  `json:"summary"`
+	Source        string  `json:"source"`
+	Severity      string  `json:"severity"`
+	CustomDetails Problem `json:"custom_details"`
+}
+
+func (n *PagerDutyNotifier) Notify(ctx context.Context, problems []Problem) error {
+	severity := n.cfg.Severity
+	if severity == "" {
+		severity = "warning"
+	}
+
+	for _, problem := range problems {
+		event := pagerDutyEvent{
+			RoutingKey:  n.cfg.RoutingKey,
+			EventAction: "trigger",
+			DedupKey:    problem.ID,
+			Payload: pagerDutyEventPayload{
+				Summary:       fmt.Sprintf("Cloud Guard: %s %s (%s)", problem.RiskLevel, problem.ResourceName, problem.Detector),
+				Source:        problem.Region,
+				Severity:      severity,
+				CustomDetails: problem,
+			},
+		}
+
+		body, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal pagerduty event: %v", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, pagerDutyEventsURL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to build pagerduty request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := n.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to post to pagerduty for problem %s: %v", problem.ID, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("pagerduty returned status %d for problem %s", resp.StatusCode, problem.ID)
+		}
+	}
+
+	return nil
+}
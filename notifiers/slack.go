@@ -0,0 +1,67 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SlackConfig configures a Slack incoming webhook notifier.
+type SlackConfig struct {
+	WebhookURL string `yaml:"webhook_url"`
+	Channel    string `yaml:"channel,omitempty"`
+}
+
+// SlackNotifier posts newly detected problems to a Slack incoming webhook,
+// one message per problem batch.
+type SlackNotifier struct {
+	cfg    SlackConfig
+	client *http.Client
+}
+
+// NewSlackNotifier builds a SlackNotifier from cfg.
+func NewSlackNotifier(cfg SlackConfig) *SlackNotifier {
+	return &SlackNotifier{cfg: cfg, client: http.DefaultClient}
+}
+
+type slackPayload struct {
+	Channel string `json:"channel,omitempty"`
+	Text    string `json:"text"`
+}
+
+func (n *SlackNotifier) Notify(ctx context.Context, problems []Problem) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	text := fmt.Sprintf("*Cloud Guard: %d new/escalated problem(s)*\n", len(problems))
+	for _, problem := range problems {
+		text += fmt.Sprintf("- [%s] %s `%s` in %s (%s) — %d days old\n",
+			problem.RiskLevel, problem.ResourceType, problem.ResourceName, problem.Region, problem.Detector, problem.DaysSinceDetection)
+	}
+
+	body, err := json.Marshal(slackPayload{Channel: n.cfg.Channel, Text: text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build slack request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to slack: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WebhookConfig configures a generic HTTP webhook notifier. AuthType may be
+// "bearer" or "basic"; Splunk HTTP Event Collector endpoints accept a
+// bearer-style token (header value "Splunk <token>"), selected via
+// AuthType "splunk".
+type WebhookConfig struct {
+	URL      string `yaml:"url"`
+	AuthType string `yaml:"auth_type,omitempty"`
+	Token    string `yaml:"token,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+}
+
+// WebhookNotifier POSTs the full problem batch as a JSON array to a
+// configured URL, with optional bearer/basic/Splunk HEC authentication.
+type WebhookNotifier struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+// NewWebhookNotifier builds a WebhookNotifier from cfg.
+func NewWebhookNotifier(cfg WebhookConfig) *WebhookNotifier {
+	return &WebhookNotifier{cfg: cfg, client: http.DefaultClient}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, problems []Problem) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(problems)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	switch n.cfg.AuthType {
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+n.cfg.Token)
+	case "basic":
+		req.SetBasicAuth(n.cfg.Username, n.cfg.Password)
+	case "splunk":
+		req.Header.Set("Authorization", "Splunk "+n.cfg.Token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookNotifierNotify(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        WebhookConfig
+		wantAuth   string
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name:     "bearer auth",
+			cfg:      WebhookConfig{AuthType: "bearer", Token: "tok123"},
+			wantAuth: "Bearer tok123",
+		},
+		{
+			name:     "splunk auth",
+			cfg:      WebhookConfig{AuthType: "splunk", Token: "tok123"},
+			wantAuth: "Splunk tok123",
+		},
+		{
+			name:     "basic auth",
+			cfg:      WebhookConfig{AuthType: "basic", Username: "user", Password: "pass"},
+			wantAuth: "", // checked separately via req.BasicAuth()
+		},
+		{
+			name:       "non-2xx response is an error",
+			cfg:        WebhookConfig{},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+
+	problems := []Problem{{ID: "p1", RiskLevel: "HIGH"}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAuthHeader string
+			var gotBasicUser, gotBasicPass string
+			var gotBody []Problem
+
+			status := tt.wantStatus
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAuthHeader = r.Header.Get("Authorization")
+				gotBasicUser, gotBasicPass, _ = r.BasicAuth()
+				if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+					t.Errorf("server failed to decode request body: %v", err)
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			cfg := tt.cfg
+			cfg.URL = server.URL
+			notifier := NewWebhookNotifier(cfg)
+
+			err := notifier.Notify(context.Background(), problems)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Notify() error = nil, want an error for a non-2xx response")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Notify() error: %v", err)
+			}
+
+			if tt.cfg.AuthType == "basic" {
+				if gotBasicUser != tt.cfg.Username || gotBasicPass != tt.cfg.Password {
+					t.Errorf("basic auth = (%q, %q), want (%q, %q)", gotBasicUser, gotBasicPass, tt.cfg.Username, tt.cfg.Password)
+				}
+			} else if gotAuthHeader != tt.wantAuth {
+				t.Errorf("Authorization header = %q, want %q", gotAuthHeader, tt.wantAuth)
+			}
+
+			if len(gotBody) != 1 || gotBody[0].ID != "p1" {
+				t.Errorf("request body = %+v, want the single problem p1", gotBody)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifierNotifyNoProblems(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(WebhookConfig{URL: server.URL})
+	if err := notifier.Notify(context.Background(), nil); err != nil {
+		t.Fatalf("Notify() error: %v", err)
+	}
+	if called {
+		t.Error("Notify() made an HTTP request for an empty problem list, want none")
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/eugsim1/cloudguard-activity-reporter/notifiers"
+)
+
+// notifyState is the on-disk record of problems already seen by a previous
+// run, keyed by problem ID, so re-running the tool on a schedule doesn't
+// re-notify on problems whose risk level hasn't changed.
+type notifyState struct {
+	Problems map[string]string `json:"problems"` // problem ID -> risk level last seen
+}
+
+func loadNotifyState(path string) (*notifyState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &notifyState{Problems: make(map[string]string)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notify state file: %v", err)
+	}
+
+	var state notifyState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse notify state file: %v", err)
+	}
+	if state.Problems == nil {
+		state.Problems = make(map[string]string)
+	}
+	return &state, nil
+}
+
+func (s *notifyState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal notify state: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write notify state file: %v", err)
+	}
+	return nil
+}
+
+// newOrEscalated returns the problems that either weren't in state at all
+// (new) or whose risk level has increased since last seen (escalated), and
+// records every problem's current risk level back into state.
+func (s *notifyState) newOrEscalated(problems []DetectedProblem) []DetectedProblem {
+	var changed []DetectedProblem
+
+	for _, problem := range problems {
+		previousRisk, seen := s.Problems[problem.ID]
+		if !seen || riskLevelRank(problem.RiskLevel) > riskLevelRank(previousRisk) {
+			changed = append(changed, problem)
+		}
+		s.Problems[problem.ID] = problem.RiskLevel
+	}
+
+	return changed
+}
+
+func riskLevelRank(level string) int {
+	switch level {
+	case "CRITICAL":
+		return 4
+	case "HIGH":
+		return 3
+	case "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func toNotifierProblem(problem DetectedProblem) notifiers.Problem {
+	return notifiers.Problem{
+		ID:                 problem.ID,
+		ResourceName:       problem.ResourceName,
+		ResourceType:       problem.ResourceType,
+		Region:             problem.Region,
+		CompartmentID:      problem.CompartmentID,
+		Detector:           problem.Detector,
+		RiskLevel:          problem.RiskLevel,
+		DaysSinceDetection: problem.DaysSinceDetection,
+		Description:        problem.Description,
+		Recommendation:     problem.Recommendation,
+		Labels:             problem.Labels,
+	}
+}
+
+// runNotifiers loads configPath, diffs problems against stateFile, and
+// fires every rule whose filter matches a new-or-escalated problem.
+// Rules are evaluated independently, so one problem can trigger more than
+// one notifier if multiple rules match it.
+func runNotifiers(ctx context.Context, configPath, stateFile string, problems []DetectedProblem) error {
+	cfg, err := notifiers.LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	rules, err := notifiers.BuildRules(cfg)
+	if err != nil {
+		return err
+	}
+
+	state, err := loadNotifyState(stateFile)
+	if err != nil {
+		return err
+	}
+
+	changed := state.newOrEscalated(problems)
+	if err := state.save(stateFile); err != nil {
+		return err
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	converted := make([]notifiers.Problem, 0, len(changed))
+	for _, problem := range changed {
+		converted = append(converted, toNotifierProblem(problem))
+	}
+
+	for _, rule := range rules {
+		var matched []notifiers.Problem
+		for _, problem := range converted {
+			if rule.Filter.Matches(problem) {
+				matched = append(matched, problem)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		for _, notifier := range rule.Notifiers {
+			if err := notifier.Notify(ctx, matched); err != nil {
+				fmt.Printf("notifier error for rule %q: %v\n", rule.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
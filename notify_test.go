@@ -0,0 +1,80 @@
+package main
+
+import "testing"
+
+func TestRiskLevelRank(t *testing.T) {
+	tests := []struct {
+		level string
+		want  int
+	}{
+		{"CRITICAL", 4},
+		{"HIGH", 3},
+		{"MEDIUM", 2},
+		{"LOW", 1},
+		{"", 0},
+		{"UNKNOWN", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			if got := riskLevelRank(tt.level); got != tt.want {
+				t.Errorf("riskLevelRank(%q) = %d, want %d", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyStateNewOrEscalated(t *testing.T) {
+	tests := []struct {
+		name    string
+		seeded  map[string]string
+		current []DetectedProblem
+		wantIDs []string
+	}{
+		{
+			name:    "unseen problem is new",
+			seeded:  map[string]string{},
+			current: []DetectedProblem{{ID: "p1", RiskLevel: "HIGH"}},
+			wantIDs: []string{"p1"},
+		},
+		{
+			name:    "unchanged risk level is not reported",
+			seeded:  map[string]string{"p1": "HIGH"},
+			current: []DetectedProblem{{ID: "p1", RiskLevel: "HIGH"}},
+			wantIDs: nil,
+		},
+		{
+			name:    "risk level increase is escalated",
+			seeded:  map[string]string{"p1": "MEDIUM"},
+			current: []DetectedProblem{{ID: "p1", RiskLevel: "CRITICAL"}},
+			wantIDs: []string{"p1"},
+		},
+		{
+			name:    "risk level decrease is not escalated",
+			seeded:  map[string]string{"p1": "CRITICAL"},
+			current: []DetectedProblem{{ID: "p1", RiskLevel: "LOW"}},
+			wantIDs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &notifyState{Problems: tt.seeded}
+			got := state.newOrEscalated(tt.current)
+
+			gotIDs := make([]string, 0, len(got))
+			for _, p := range got {
+				gotIDs = append(gotIDs, p.ID)
+			}
+			if !equalStrings(gotIDs, tt.wantIDs) {
+				t.Errorf("newOrEscalated() IDs = %v, want %v", gotIDs, tt.wantIDs)
+			}
+
+			for _, problem := range tt.current {
+				if state.Problems[problem.ID] != problem.RiskLevel {
+					t.Errorf("state.Problems[%q] = %q, want %q (current risk level recorded)", problem.ID, state.Problems[problem.ID], problem.RiskLevel)
+				}
+			}
+		})
+	}
+}
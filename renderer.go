@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Renderer writes a set of detected problems (plus the summary computed over
+// them) to an arbitrary io.Writer. Implementations back both the -output
+// file and stdout, so the same rendering logic is used regardless of
+// destination.
+type Renderer interface {
+	Render(w io.Writer, problems []DetectedProblem, summary ActivitySummary) error
+}
+
+// rendererForFormat returns the Renderer for the given -format value.
+func rendererForFormat(format string) (Renderer, error) {
+	switch strings.ToLower(format) {
+	case "csv":
+		return CSVRenderer{}, nil
+	case "json":
+		return JSONRenderer{}, nil
+	case "jsonl", "json-lines", "jsonlines":
+		return JSONLinesRenderer{}, nil
+	case "table":
+		return TableRenderer{}, nil
+	case "raw":
+		return RawRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (want csv|json|jsonl|table|raw)", format)
+	}
+}
+
+var csvHeaders = []string{
+	"Problem_ID",
+	"First_Detected",
+	"Last_Detected",
+	"Days_Since_Detection",
+	"Resource_ID",
+	"Resource_Name",
+	"Resource_Type",
+	"Region",
+	"Compartment_ID",
+	"Detector",
+	"Risk_Level",
+	"Description",
+	"Recommendation",
+	"Detector_Rule_ID",
+	"Target_ID",
+	"Labels",
+	"Lifecycle_State",
+}
+
+func csvRow(problem DetectedProblem) []string {
+	labels := strings.Join(problem.Labels, "|")
+	if labels == "" {
+		labels = "N/A"
+	}
+
+	return []string{
+		problem.ID,
+		problem.FirstDetected.Format(time.RFC3339),
+		problem.LastDetected.Format(time.RFC3339),
+		strconv.Itoa(problem.DaysSinceDetection),
+		problem.ResourceID,
+		problem.ResourceName,
+		problem.ResourceType,
+		problem.Region,
+		problem.CompartmentID,
+		problem.Detector,
+		problem.RiskLevel,
+		problem.Description,
+		problem.Recommendation,
+		problem.DetectorRuleID,
+		problem.TargetID,
+		labels,
+		problem.LifecycleState,
+	}
+}
+
+// CSVRenderer writes problems in the same column layout the tool has always
+// produced.
+type CSVRenderer struct{}
+
+func (CSVRenderer) Render(w io.Writer, problems []DetectedProblem, summary ActivitySummary) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	if err := writer.Write(csvHeaders); err != nil {
+		return err
+	}
+
+	for _, problem := range problems {
+		if err := writer.Write(csvRow(problem)); err != nil {
+			return err
+		}
+	}
+
+	return writer.Error()
+}
+
+// JSONRenderer writes a single JSON object containing the summary and the
+// full problem list, pretty-printed for humans reading a saved report.
+type JSONRenderer struct{}
+
+func (JSONRenderer) Render(w io.Writer, problems []DetectedProblem, summary ActivitySummary) error {
+	payload := struct {
+		Summary  ActivitySummary   `json:"summary"`
+		Problems []DetectedProblem `json:"problems"`
+	}{
+		Summary:  summary,
+		Problems: problems,
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
+
+// JSONLinesRenderer writes one JSON object per problem, newline-delimited,
+// for piping into log pipelines (Splunk HEC, Loki, etc).
+type JSONLinesRenderer struct{}
+
+func (JSONLinesRenderer) Render(w io.Writer, problems []DetectedProblem, summary ActivitySummary) error {
+	encoder := json.NewEncoder(w)
+	for _, problem := range problems {
+		if err := encoder.Encode(problem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TableRenderer renders problems as an aligned terminal table, for ad-hoc
+// interactive use.
+type TableRenderer struct{}
+
+func (TableRenderer) Render(w io.Writer, problems []DetectedProblem, summary ActivitySummary) error {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Risk", "Resource Type", "Resource Name", "Region", "Detector", "Days", "Description"})
+	table.SetAutoWrapText(false)
+
+	for _, problem := range problems {
+		table.Append([]string{
+			problem.RiskLevel,
+			problem.ResourceType,
+			problem.ResourceName,
+			problem.Region,
+			problem.Detector,
+			strconv.Itoa(problem.DaysSinceDetection),
+			truncateString(problem.Description, 60),
+		})
+	}
+
+	table.Render()
+	fmt.Fprintf(w, "\n%d problem(s)\n", summary.TotalProblems)
+	return nil
+}
+
+// RawRenderer reproduces the tool's original plain-text summary, so
+// -format=raw writes it to the -output destination as well as the copy
+// main always prints to stdout.
+type RawRenderer struct{}
+
+func (RawRenderer) Render(w io.Writer, problems []DetectedProblem, summary ActivitySummary) error {
+	printDetailedSummaryTo(w, summary)
+	return nil
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleProblems() []DetectedProblem {
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	return []DetectedProblem{
+		{
+			ID:                 "p1",
+			FirstDetected:      first,
+			LastDetected:       last,
+			DaysSinceDetection: 5,
+			ResourceID:         "r1",
+			ResourceName:       "bucket-1",
+			ResourceType:       "ObjectStorage",
+			Region:             "us-ashburn-1",
+			CompartmentID:      "c1",
+			Detector:           "ConfigurationDetector",
+			RiskLevel:          "HIGH",
+			Description:        strings.Repeat("x", 80),
+			Recommendation:     "lock it down",
+			DetectorRuleID:     "rule1",
+			TargetID:           "t1",
+			Labels:             []string{"pci-scope", "public-access"},
+			LifecycleState:     "ACTIVE",
+		},
+		{
+			ID:           "p2",
+			ResourceType: "Instance",
+			RiskLevel:    "LOW",
+		},
+	}
+}
+
+func TestRendererForFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		want   Renderer
+	}{
+		{"csv", CSVRenderer{}},
+		{"CSV", CSVRenderer{}},
+		{"json", JSONRenderer{}},
+		{"jsonl", JSONLinesRenderer{}},
+		{"json-lines", JSONLinesRenderer{}},
+		{"jsonlines", JSONLinesRenderer{}},
+		{"table", TableRenderer{}},
+		{"raw", RawRenderer{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := rendererForFormat(tt.format)
+			if err != nil {
+				t.Fatalf("rendererForFormat(%q) error: %v", tt.format, err)
+			}
+			if got != tt.want {
+				t.Errorf("rendererForFormat(%q) = %#v, want %#v", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRendererForFormatUnknown(t *testing.T) {
+	if _, err := rendererForFormat("xml"); err == nil {
+		t.Error("rendererForFormat(\"xml\") error = nil, want an error")
+	}
+}
+
+func TestCSVRendererRender(t *testing.T) {
+	var buf bytes.Buffer
+	problems := sampleProblems()
+	summary := ActivitySummary{TotalProblems: len(problems)}
+
+	if err := (CSVRenderer{}).Render(&buf, problems, summary); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse rendered CSV: %v", err)
+	}
+
+	if len(rows) != len(problems)+1 {
+		t.Fatalf("got %d rows, want %d (header + %d problems)", len(rows), len(problems)+1, len(problems))
+	}
+	if !equalStrings(rows[0], csvHeaders) {
+		t.Errorf("header row = %v, want %v", rows[0], csvHeaders)
+	}
+
+	first := rows[1]
+	if first[0] != "p1" {
+		t.Errorf("row[0] (Problem_ID) = %q, want p1", first[0])
+	}
+	if first[15] != "pci-scope|public-access" {
+		t.Errorf("row[15] (Labels) = %q, want pipe-joined labels", first[15])
+	}
+
+	second := rows[2]
+	if second[15] != "N/A" {
+		t.Errorf("row[15] (Labels) for a problem with no labels = %q, want N/A", second[15])
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestJSONRendererRender(t *testing.T) {
+	var buf bytes.Buffer
+	problems := sampleProblems()
+	summary := ActivitySummary{TotalProblems: len(problems)}
+
+	if err := (JSONRenderer{}).Render(&buf, problems, summary); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	var payload struct {
+		Summary  ActivitySummary   `json:"summary"`
+		Problems []DetectedProblem `json:"problems"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("failed to parse rendered JSON: %v", err)
+	}
+
+	if payload.Summary.TotalProblems != len(problems) {
+		t.Errorf("summary.TotalProblems = %d, want %d", payload.Summary.TotalProblems, len(problems))
+	}
+	if len(payload.Problems) != len(problems) {
+		t.Fatalf("len(problems) = %d, want %d", len(payload.Problems), len(problems))
+	}
+	if payload.Problems[0].ID != "p1" {
+		t.Errorf("problems[0].ID = %q, want p1", payload.Problems[0].ID)
+	}
+}
+
+func TestJSONLinesRendererRender(t *testing.T) {
+	var buf bytes.Buffer
+	problems := sampleProblems()
+	summary := ActivitySummary{TotalProblems: len(problems)}
+
+	if err := (JSONLinesRenderer{}).Render(&buf, problems, summary); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(problems) {
+		t.Fatalf("got %d lines, want %d (one per problem, unwrapped)", len(lines), len(problems))
+	}
+
+	var first DetectedProblem
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("line 0 is not a standalone problem object: %v", err)
+	}
+	if first.ID != "p1" {
+		t.Errorf("line 0 ID = %q, want p1", first.ID)
+	}
+}
+
+func TestTableRendererRender(t *testing.T) {
+	var buf bytes.Buffer
+	problems := sampleProblems()
+	summary := ActivitySummary{TotalProblems: len(problems)}
+
+	if err := (TableRenderer{}).Render(&buf, problems, summary); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	out := buf.String()
+	for _, header := range []string{"RISK", "RESOURCE TYPE", "RESOURCE NAME", "REGION", "DETECTOR", "DAYS", "DESCRIPTION"} {
+		if !strings.Contains(out, header) {
+			t.Errorf("table output missing header %q:\n%s", header, out)
+		}
+	}
+	if !strings.Contains(out, truncateString(problems[0].Description, 60)) {
+		t.Errorf("table output missing truncated description:\n%s", out)
+	}
+	if strings.Contains(out, problems[0].Description) {
+		t.Errorf("table output contains the untruncated description, want it truncated:\n%s", out)
+	}
+	if !strings.Contains(out, "2 problem(s)") {
+		t.Errorf("table output missing summary count line:\n%s", out)
+	}
+}
+
+func TestRawRendererRender(t *testing.T) {
+	var buf bytes.Buffer
+	summary := ActivitySummary{TotalProblems: 3}
+
+	if err := (RawRenderer{}).Render(&buf, nil, summary); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	var want bytes.Buffer
+	printDetailedSummaryTo(&want, summary)
+
+	if buf.String() != want.String() {
+		t.Errorf("RawRenderer output = %q, want %q", buf.String(), want.String())
+	}
+}
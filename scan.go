@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/oracle/oci-go-sdk/v65/cloudguard"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"golang.org/x/sync/errgroup"
+)
+
+// ScanConfig describes the set of regions and compartments a scan should
+// cover, and how much parallelism to apply while covering them.
+type ScanConfig struct {
+	Regions          []string
+	Compartments     []string
+	RecursiveSubtree bool
+	Workers          int
+}
+
+// scanTarget is a single region/compartment pair to be queried.
+type scanTarget struct {
+	Region        string
+	CompartmentID string
+}
+
+// runScan fans a filter out across every region/compartment pair in cfg,
+// using an errgroup-backed worker pool bounded by cfg.Workers, and merges
+// the results into a single deduplicated problem list keyed by problem ID.
+// Progress (one tick per page fetched, one per problem enriched, one per
+// finished target) is written to stderr via a cheggaaa/pb bar whose total
+// grows alongside the ticks, so long tenancy-wide scans show a live,
+// never-overshooting percentage and ETA.
+func runScan(ctx context.Context, identityClient identity.IdentityClient, baseFilter ActivityFilter, cfg ScanConfig) ([]DetectedProblem, error) {
+	compartments, err := resolveCompartments(ctx, identityClient, cfg.Compartments, cfg.RecursiveSubtree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve compartments: %v", err)
+	}
+
+	regions, err := resolveRegions(ctx, identityClient, cfg.Regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve regions: %v", err)
+	}
+
+	var targets []scanTarget
+	for _, region := range regions {
+		for _, compartmentID := range compartments {
+			targets = append(targets, scanTarget{Region: region, CompartmentID: compartmentID})
+		}
+	}
+
+	// One unit per target, counted when that target's scan finishes;
+	// getDetectedProblemsWithProgress grows the total further as it
+	// discovers pages and problems to enrich, so the bar's percentage
+	// never jumps past 100% the way a fixed upfront estimate would.
+	bar := pb.New(len(targets))
+	bar.SetWriter(os.Stderr)
+	bar.Start()
+	defer bar.Finish()
+
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(workers)
+
+	var (
+		mu     sync.Mutex
+		merged = make(map[string]DetectedProblem)
+	)
+
+	for _, target := range targets {
+		target := target
+		group.Go(func() error {
+			client, err := newRegionalCloudGuardClient(target.Region)
+			if err != nil {
+				return fmt.Errorf("region %s: %v", target.Region, err)
+			}
+
+			filter := baseFilter
+			filter.CompartmentID = target.CompartmentID
+			filter.Region = ""
+
+			problems, err := getDetectedProblemsWithProgress(groupCtx, client, filter, bar)
+			if err != nil {
+				return fmt.Errorf("region %s compartment %s: %v", target.Region, target.CompartmentID, err)
+			}
+
+			mu.Lock()
+			for _, problem := range problems {
+				merged[problem.ID] = problem
+			}
+			bar.Increment()
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	results := make([]DetectedProblem, 0, len(merged))
+	for _, problem := range merged {
+		results = append(results, problem)
+	}
+
+	return results, nil
+}
+
+// resolveRegions expands the -regions flag. A literal "all" entry is
+// replaced with every region the tenancy is subscribed to, via the
+// Identity SDK's ListRegionSubscriptions; any other entries are passed
+// through unchanged.
+func resolveRegions(ctx context.Context, identityClient identity.IdentityClient, regions []string) ([]string, error) {
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("at least one region is required")
+	}
+
+	var resolved []string
+	for _, region := range regions {
+		if region != "all" {
+			resolved = append(resolved, region)
+			continue
+		}
+
+		subscribed, err := listSubscribedRegions(ctx, identityClient)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to expand "all": %v`, err)
+		}
+		resolved = append(resolved, subscribed...)
+	}
+
+	return dedupeStrings(resolved), nil
+}
+
+// listSubscribedRegions returns every region the tenancy is subscribed to.
+func listSubscribedRegions(ctx context.Context, identityClient identity.IdentityClient) ([]string, error) {
+	tenancyID, err := common.DefaultConfigProvider().TenancyOCID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine tenancy OCID: %v", err)
+	}
+
+	response, err := identityClient.ListRegionSubscriptions(ctx, identity.ListRegionSubscriptionsRequest{
+		TenancyId: common.String(tenancyID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list region subscriptions: %v", err)
+	}
+
+	var regions []string
+	for _, subscription := range response.Items {
+		if subscription.RegionName != nil {
+			regions = append(regions, *subscription.RegionName)
+		}
+	}
+
+	return regions, nil
+}
+
+func dedupeStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	deduped := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			deduped = append(deduped, v)
+		}
+	}
+	return deduped
+}
+
+// resolveCompartments expands explicit compartment OCIDs and, when
+// recursive is set, walks each one's subtree via the Identity SDK.
+func resolveCompartments(ctx context.Context, identityClient identity.IdentityClient, compartments []string, recursive bool) ([]string, error) {
+	if !recursive {
+		return compartments, nil
+	}
+
+	seen := make(map[string]bool)
+	var all []string
+
+	for _, root := range compartments {
+		if !seen[root] {
+			seen[root] = true
+			all = append(all, root)
+		}
+
+		children, err := listCompartmentSubtree(ctx, identityClient, root)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if !seen[child] {
+				seen[child] = true
+				all = append(all, child)
+			}
+		}
+	}
+
+	return all, nil
+}
+
+// listCompartmentSubtree returns every ACTIVE descendant compartment OCID beneath
+// root using the Identity SDK's recursive ListCompartments mode.
+func listCompartmentSubtree(ctx context.Context, identityClient identity.IdentityClient, root string) ([]string, error) {
+	var ids []string
+	var page *string
+
+	for {
+		request := identity.ListCompartmentsRequest{
+			CompartmentId:          common.String(root),
+			CompartmentIdInSubtree: common.Bool(true),
+			LifecycleState:         identity.CompartmentLifecycleStateActive,
+			Page:                   page,
+		}
+
+		response, err := identityClient.ListCompartments(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list compartment subtree for %s: %v", root, err)
+		}
+
+		for _, compartment := range response.Items {
+			ids = append(ids, safeString(compartment.Id))
+		}
+
+		if response.OpcNextPage == nil {
+			break
+		}
+		page = response.OpcNextPage
+	}
+
+	return ids, nil
+}
+
+// newRegionalCloudGuardClient builds a Cloud Guard client pinned to region,
+// so concurrent scanners each own an independent client instance rather
+// than racing on a single client's region field.
+func newRegionalCloudGuardClient(region string) (cloudguard.CloudGuardClient, error) {
+	client, err := cloudguard.NewCloudGuardClientWithConfigurationProvider(common.DefaultConfigProvider())
+	if err != nil {
+		return cloudguard.CloudGuardClient{}, err
+	}
+	client.SetRegion(region)
+	return client, nil
+}
+
+// barTicker adapts a *pb.ProgressBar to progressTicker, so the shared
+// fetchDetectedProblems loop in cloudguard_activity.go can drive it
+// without cloudguard_activity.go importing cheggaaa/pb.
+type barTicker struct {
+	bar *pb.ProgressBar
+}
+
+func (t barTicker) addTotal()  { t.bar.AddTotal(1) }
+func (t barTicker) increment() { t.bar.Increment() }
+
+// getDetectedProblemsWithProgress is getDetectedProblems's counterpart for
+// the concurrent scan path: same pagination/enrichment loop, but ticking
+// bar as it goes so long tenancy-wide scans show live progress.
+func getDetectedProblemsWithProgress(ctx context.Context, client cloudguard.CloudGuardClient, filter ActivityFilter, bar *pb.ProgressBar) ([]DetectedProblem, error) {
+	return fetchDetectedProblems(ctx, client, filter, barTicker{bar: bar})
+}
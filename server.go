@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/cloudguard"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	problemsTotal = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cloudguard_problems_total",
+		Help: "Number of currently open Cloud Guard problems, by dimension.",
+	}, []string{"risk_level", "region", "resource_type", "detector"})
+
+	problemAgeDays = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "cloudguard_problem_age_days",
+		Help:    "Age in days (since first detection) of currently open Cloud Guard problems.",
+		Buckets: []float64{1, 3, 7, 14, 30, 60, 90, 180, 365},
+	})
+
+	scanDurationSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "cloudguard_scan_duration_seconds",
+		Help:    "Time taken to complete a full scan cycle in serve mode.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+// problemStore holds the most recent scan's results for the /problems and
+// /readyz endpoints to read while the next scan runs in the background.
+type problemStore struct {
+	mu       sync.RWMutex
+	problems []DetectedProblem
+	ready    bool
+}
+
+func (s *problemStore) set(problems []DetectedProblem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.problems = problems
+	s.ready = true
+}
+
+func (s *problemStore) get() ([]DetectedProblem, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.problems, s.ready
+}
+
+// runServe implements the `serve` subcommand: it re-runs the scanner on
+// -interval and exposes the results over HTTP for Prometheus scraping and
+// ad-hoc querying, instead of writing a one-shot report and exiting.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	compartmentID := fs.String("compartment-id", "", "Compartment ID (required)")
+	regionsFlag := fs.String("regions", "", "Comma-separated regions to scan each cycle")
+	compartmentsFlag := fs.String("compartments", "", "Comma-separated compartment OCIDs to scan each cycle (overrides -compartment-id)")
+	recursiveCompartments := fs.Bool("recursive-compartments", false, "Recursively include each compartment's subtree via the Identity SDK")
+	workers := fs.Int("workers", 4, "Concurrent worker count per scan cycle")
+	daysBack := fs.Int("days", 7, "Number of days back to search each cycle")
+	interval := fs.Duration("interval", 15*time.Minute, "How often to re-run the scan")
+	listenAddr := fs.String("listen-addr", ":9090", "Address to serve /metrics, /problems, /healthz and /readyz on")
+	fs.Parse(args)
+
+	if *compartmentID == "" {
+		*compartmentID = os.Getenv("OCI_COMPARTMENT_ID")
+	}
+	if *compartmentID == "" && *compartmentsFlag == "" {
+		fmt.Println("Error: -compartment-id or -compartments is required")
+		os.Exit(1)
+	}
+
+	var regionList []string
+	if *regionsFlag != "" {
+		regionList = splitAndTrim(*regionsFlag)
+	}
+	var compartmentList []string
+	if *compartmentsFlag != "" {
+		compartmentList = splitAndTrim(*compartmentsFlag)
+	} else {
+		compartmentList = []string{*compartmentID}
+	}
+
+	identityClient, err := identity.NewIdentityClientWithConfigurationProvider(common.DefaultConfigProvider())
+	if err != nil {
+		fmt.Printf("Error creating Identity client: %v\n", err)
+		os.Exit(1)
+	}
+
+	store := &problemStore{}
+
+	scanOnce := func() {
+		start := time.Now()
+
+		endTime := time.Now()
+		filter := ActivityFilter{
+			StartTime: endTime.AddDate(0, 0, -*daysBack),
+			EndTime:   endTime,
+			Limit:     1000,
+		}
+
+		var problems []DetectedProblem
+		var scanErr error
+
+		if len(regionList) > 0 {
+			problems, scanErr = runScan(context.Background(), identityClient, filter, ScanConfig{
+				Regions:          regionList,
+				Compartments:     compartmentList,
+				RecursiveSubtree: *recursiveCompartments,
+				Workers:          *workers,
+			})
+		} else {
+			client, clientErr := cloudguard.NewCloudGuardClientWithConfigurationProvider(common.DefaultConfigProvider())
+			if clientErr != nil {
+				scanErr = clientErr
+			} else {
+				compartments, resolveErr := resolveCompartments(context.Background(), identityClient, compartmentList, *recursiveCompartments)
+				if resolveErr != nil {
+					scanErr = resolveErr
+				}
+				for _, compartment := range compartments {
+					filter.CompartmentID = compartment
+					found, err := getDetectedProblems(client, filter)
+					if err != nil {
+						scanErr = err
+						break
+					}
+					problems = append(problems, found...)
+				}
+			}
+		}
+
+		scanDurationSeconds.Observe(time.Since(start).Seconds())
+
+		if scanErr != nil {
+			fmt.Printf("scan failed: %v\n", scanErr)
+			return
+		}
+
+		store.set(problems)
+		updateProblemMetrics(problems)
+		fmt.Printf("scan complete: %d problem(s) in %s\n", len(problems), time.Since(start).Round(time.Millisecond))
+	}
+
+	go func() {
+		scanOnce()
+		ticker := time.NewTicker(*interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			scanOnce()
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/problems", problemsHandler(store))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if _, ready := store.get(); !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, "not ready: first scan has not completed")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ready")
+	})
+
+	fmt.Printf("Serving on %s (scan interval %s)\n", *listenAddr, *interval)
+	if err := http.ListenAndServe(*listenAddr, mux); err != nil {
+		fmt.Printf("server error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// updateProblemMetrics resets and repopulates problemsTotal from the latest
+// scan, so gauges never report stale dimension values for problems that
+// have since been resolved. problemAgeDays is not reset: each scan adds one
+// observation per problem to it.
+func updateProblemMetrics(problems []DetectedProblem) {
+	problemsTotal.Reset()
+	counts := make(map[[4]string]int)
+
+	for _, problem := range problems {
+		key := [4]string{problem.RiskLevel, problem.Region, problem.ResourceType, problem.Detector}
+		counts[key]++
+		problemAgeDays.Observe(float64(problem.DaysSinceDetection))
+	}
+
+	for key, count := range counts {
+		problemsTotal.WithLabelValues(key[0], key[1], key[2], key[3]).Set(float64(count))
+	}
+}
+
+// problemsHandler serves the current problem set as JSON, filtered by
+// exact-match query parameters (risk_level, resource_type, region,
+// detector, problem_id) and a minimum age (min_age_days).
+func problemsHandler(store *problemStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		problems, ready := store.get()
+		if !ready {
+			http.Error(w, "first scan has not completed yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		query := r.URL.Query()
+		filtered := make([]DetectedProblem, 0, len(problems))
+
+		var minAgeDays int
+		if v := query.Get("min_age_days"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid min_age_days: %v", err), http.StatusBadRequest)
+				return
+			}
+			minAgeDays = parsed
+		}
+
+		for _, problem := range problems {
+			if v := query.Get("risk_level"); v != "" && problem.RiskLevel != v {
+				continue
+			}
+			if v := query.Get("resource_type"); v != "" && problem.ResourceType != v {
+				continue
+			}
+			if v := query.Get("region"); v != "" && problem.Region != v {
+				continue
+			}
+			if v := query.Get("detector"); v != "" && problem.Detector != v {
+				continue
+			}
+			if v := query.Get("problem_id"); v != "" && problem.ID != v {
+				continue
+			}
+			if problem.DaysSinceDetection < minAgeDays {
+				continue
+			}
+			filtered = append(filtered, problem)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(filtered)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func decodeProblems(t *testing.T, rec *httptest.ResponseRecorder) []DetectedProblem {
+	t.Helper()
+	var problems []DetectedProblem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problems); err != nil {
+		t.Fatalf("failed to decode response body %q: %v", rec.Body.String(), err)
+	}
+	return problems
+}
+
+func TestProblemsHandlerNotReady(t *testing.T) {
+	store := &problemStore{}
+	handler := problemsHandler(store)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/problems", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestProblemsHandlerFiltering(t *testing.T) {
+	store := &problemStore{}
+	store.set([]DetectedProblem{
+		{ID: "p1", RiskLevel: "HIGH", ResourceType: "ObjectStorage", Region: "us-ashburn-1", Detector: "ConfigurationDetector", DaysSinceDetection: 10},
+		{ID: "p2", RiskLevel: "LOW", ResourceType: "Instance", Region: "us-phoenix-1", Detector: "ActivityDetector", DaysSinceDetection: 1},
+	})
+	handler := problemsHandler(store)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantIDs    []string
+	}{
+		{name: "no filter returns everything", query: "", wantIDs: []string{"p1", "p2"}},
+		{name: "risk_level match", query: "risk_level=HIGH", wantIDs: []string{"p1"}},
+		{name: "risk_level no match", query: "risk_level=CRITICAL", wantIDs: []string{}},
+		{name: "resource_type match", query: "resource_type=Instance", wantIDs: []string{"p2"}},
+		{name: "region match", query: "region=us-ashburn-1", wantIDs: []string{"p1"}},
+		{name: "detector match", query: "detector=ActivityDetector", wantIDs: []string{"p2"}},
+		{name: "problem_id match", query: "problem_id=p1", wantIDs: []string{"p1"}},
+		{name: "min_age_days filters out younger problems", query: "min_age_days=5", wantIDs: []string{"p1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/problems?"+tt.query, nil)
+			handler(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+
+			got := decodeProblems(t, rec)
+			gotIDs := make([]string, 0, len(got))
+			for _, p := range got {
+				gotIDs = append(gotIDs, p.ID)
+			}
+			if !equalStrings(gotIDs, tt.wantIDs) {
+				t.Errorf("IDs = %v, want %v", gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestProblemsHandlerInvalidMinAgeDays(t *testing.T) {
+	store := &problemStore{}
+	store.set([]DetectedProblem{{ID: "p1"}})
+	handler := problemsHandler(store)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/problems?min_age_days=not-a-number", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestUpdateProblemMetrics(t *testing.T) {
+	updateProblemMetrics([]DetectedProblem{
+		{RiskLevel: "HIGH", Region: "us-ashburn-1", ResourceType: "ObjectStorage", Detector: "ConfigurationDetector", DaysSinceDetection: 2},
+		{RiskLevel: "HIGH", Region: "us-ashburn-1", ResourceType: "ObjectStorage", Detector: "ConfigurationDetector", DaysSinceDetection: 3},
+	})
+
+	got := testutil.ToFloat64(problemsTotal.WithLabelValues("HIGH", "us-ashburn-1", "ObjectStorage", "ConfigurationDetector"))
+	if got != 2 {
+		t.Errorf("problemsTotal = %v, want 2", got)
+	}
+
+	// A second call with a disjoint dimension set must not leave the first
+	// call's series behind (Reset before repopulating).
+	updateProblemMetrics([]DetectedProblem{
+		{RiskLevel: "LOW", Region: "us-phoenix-1", ResourceType: "Instance", Detector: "ActivityDetector", DaysSinceDetection: 1},
+	})
+
+	got = testutil.ToFloat64(problemsTotal.WithLabelValues("HIGH", "us-ashburn-1", "ObjectStorage", "ConfigurationDetector"))
+	if got != 0 {
+		t.Errorf("problemsTotal for the stale series = %v, want 0 after Reset", got)
+	}
+	got = testutil.ToFloat64(problemsTotal.WithLabelValues("LOW", "us-phoenix-1", "Instance", "ActivityDetector"))
+	if got != 1 {
+		t.Errorf("problemsTotal for the new series = %v, want 1", got)
+	}
+}
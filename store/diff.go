@@ -0,0 +1,70 @@
+package store
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// Escalation records a problem whose risk level increased between two
+// scans.
+type Escalation struct {
+	Problem Problem
+	From    string
+	To      string
+}
+
+// DiffReport is what changed in a single RecordAndDiff call relative to
+// the store's prior state.
+type DiffReport struct {
+	New          []Problem
+	Resolved     []Problem
+	Escalated    []Escalation
+	LongStanding []Problem
+}
+
+// PrintSummary writes a short human-readable summary of the diff to w,
+// mirroring the tone of the tool's existing summary output.
+func (r DiffReport) PrintSummary(w io.Writer) {
+	fmt.Fprintf(w, "\n=== CLOUD GUARD DIFF SINCE LAST SCAN ===\n")
+	fmt.Fprintf(w, "New:          %d\n", len(r.New))
+	fmt.Fprintf(w, "Resolved:     %d\n", len(r.Resolved))
+	fmt.Fprintf(w, "Escalated:    %d\n", len(r.Escalated))
+	fmt.Fprintf(w, "Long-standing: %d\n", len(r.LongStanding))
+}
+
+// WriteCSV writes every row of the diff report (tagged with a Change_Type
+// column) to w, for the cloudguard_diff_<timestamp>.csv diff report.
+func (r DiffReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	headers := []string{"Change_Type", "Problem_ID", "Resource_Type", "Resource_Name", "Region", "Detector", "Risk_Level", "From_Risk_Level"}
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+
+	for _, problem := range r.New {
+		if err := writer.Write([]string{"NEW", problem.ID, problem.ResourceType, problem.ResourceName, problem.Region, problem.Detector, problem.RiskLevel, ""}); err != nil {
+			return err
+		}
+	}
+	for _, problem := range r.Resolved {
+		if err := writer.Write([]string{"RESOLVED", problem.ID, problem.ResourceType, problem.ResourceName, problem.Region, problem.Detector, problem.RiskLevel, ""}); err != nil {
+			return err
+		}
+	}
+	for _, escalation := range r.Escalated {
+		problem := escalation.Problem
+		if err := writer.Write([]string{"ESCALATED", problem.ID, problem.ResourceType, problem.ResourceName, problem.Region, problem.Detector, escalation.To, escalation.From}); err != nil {
+			return err
+		}
+	}
+	for _, problem := range r.LongStanding {
+		if err := writer.Write([]string{"LONG_STANDING", problem.ID, problem.ResourceType, problem.ResourceName, problem.Region, problem.Detector, problem.RiskLevel, ""}); err != nil {
+			return err
+		}
+	}
+
+	return writer.Error()
+}
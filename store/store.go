@@ -0,0 +1,254 @@
+// Package store keeps a SQLite-backed history of every Cloud Guard
+// problem the tool has recorded, so RecordAndDiff can classify each scan's
+// results against what was open last time: new, resolved, escalated (risk
+// level increased), or long-standing (open past a configurable age).
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Problem is the subset of a detected Cloud Guard problem the store needs
+// to track lifecycle and risk-level history for.
+type Problem struct {
+	ID                 string
+	ResourceName       string
+	ResourceType       string
+	Region             string
+	Detector           string
+	RiskLevel          string
+	LifecycleState     string
+	DaysSinceDetection int
+}
+
+// Store is a SQLite-backed history of every problem the tool has ever
+// seen, across runs.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (creating if necessary) the SQLite database at path and
+// ensures its schema exists.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state db: %v", err)
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate state db: %v", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close releases the underlying database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS problems (
+			id               TEXT PRIMARY KEY,
+			resource_name    TEXT,
+			resource_type    TEXT,
+			region           TEXT,
+			detector         TEXT,
+			risk_level       TEXT,
+			lifecycle_state  TEXT,
+			is_open          INTEGER NOT NULL,
+			first_seen_at    TIMESTAMP NOT NULL,
+			last_seen_at     TIMESTAMP NOT NULL,
+			closed_at        TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS risk_transitions (
+			problem_id  TEXT NOT NULL,
+			from_risk   TEXT NOT NULL,
+			to_risk     TEXT NOT NULL,
+			changed_at  TIMESTAMP NOT NULL
+		);
+	`)
+	return err
+}
+
+// riskLevelRank orders risk levels so a transition can be classified as an
+// escalation (rank increases) rather than just "changed".
+func riskLevelRank(level string) int {
+	switch level {
+	case "CRITICAL":
+		return 4
+	case "HIGH":
+		return 3
+	case "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// existingRow is the persisted state of a problem loaded before a
+// RecordAndDiff pass, used to detect what changed in the incoming batch
+// and to populate descriptive columns for problems that have since
+// disappeared from the scan (resolved).
+type existingRow struct {
+	resourceName string
+	resourceType string
+	region       string
+	detector     string
+	riskLevel    string
+	isOpen       bool
+}
+
+// RecordAndDiff updates the store with the current batch of problems and
+// returns everything that changed relative to the store's prior state:
+// newly seen problems, problems no longer present (resolved), risk-level
+// escalations, and problems that have been open longer than
+// longStandingDays.
+func (s *Store) RecordAndDiff(problems []Problem, longStandingDays int) (DiffReport, error) {
+	now := time.Now()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return DiffReport{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	existing, err := loadOpenProblems(tx)
+	if err != nil {
+		return DiffReport{}, err
+	}
+
+	var report DiffReport
+	seen := make(map[string]bool, len(problems))
+
+	for _, problem := range problems {
+		seen[problem.ID] = true
+		prior, known := existing[problem.ID]
+
+		if !known {
+			report.New = append(report.New, problem)
+			if err := insertProblem(tx, problem, now); err != nil {
+				return DiffReport{}, err
+			}
+		} else {
+			if prior.riskLevel != problem.RiskLevel {
+				if riskLevelRank(problem.RiskLevel) > riskLevelRank(prior.riskLevel) {
+					report.Escalated = append(report.Escalated, Escalation{
+						Problem: problem,
+						From:    prior.riskLevel,
+						To:      problem.RiskLevel,
+					})
+				}
+				if err := insertRiskTransition(tx, problem.ID, prior.riskLevel, problem.RiskLevel, now); err != nil {
+					return DiffReport{}, err
+				}
+			}
+			if err := touchProblem(tx, problem, now); err != nil {
+				return DiffReport{}, err
+			}
+		}
+
+		if problem.DaysSinceDetection > longStandingDays {
+			report.LongStanding = append(report.LongStanding, problem)
+		}
+	}
+
+	for id, prior := range existing {
+		if seen[id] {
+			continue
+		}
+		if err := closeProblem(tx, id, now); err != nil {
+			return DiffReport{}, err
+		}
+		report.Resolved = append(report.Resolved, Problem{
+			ID:           id,
+			ResourceName: prior.resourceName,
+			ResourceType: prior.resourceType,
+			Region:       prior.region,
+			Detector:     prior.detector,
+			RiskLevel:    prior.riskLevel,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return DiffReport{}, fmt.Errorf("failed to commit state update: %v", err)
+	}
+
+	return report, nil
+}
+
+func loadOpenProblems(tx *sql.Tx) (map[string]existingRow, error) {
+	rows, err := tx.Query(`SELECT id, resource_name, resource_type, region, detector, risk_level FROM problems WHERE is_open = 1`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing problems: %v", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]existingRow)
+	for rows.Next() {
+		var id, resourceName, resourceType, region, detector, riskLevel string
+		if err := rows.Scan(&id, &resourceName, &resourceType, &region, &detector, &riskLevel); err != nil {
+			return nil, fmt.Errorf("failed to scan existing problem row: %v", err)
+		}
+		existing[id] = existingRow{
+			resourceName: resourceName,
+			resourceType: resourceType,
+			region:       region,
+			detector:     detector,
+			riskLevel:    riskLevel,
+			isOpen:       true,
+		}
+	}
+
+	return existing, rows.Err()
+}
+
+func insertProblem(tx *sql.Tx, problem Problem, now time.Time) error {
+	_, err := tx.Exec(`
+		INSERT INTO problems (id, resource_name, resource_type, region, detector, risk_level, lifecycle_state, is_open, first_seen_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			resource_name = excluded.resource_name,
+			resource_type = excluded.resource_type,
+			region = excluded.region,
+			detector = excluded.detector,
+			risk_level = excluded.risk_level,
+			lifecycle_state = excluded.lifecycle_state,
+			is_open = 1,
+			last_seen_at = excluded.last_seen_at,
+			closed_at = NULL
+	`, problem.ID, problem.ResourceName, problem.ResourceType, problem.Region, problem.Detector, problem.RiskLevel, problem.LifecycleState, now, now)
+	return err
+}
+
+func touchProblem(tx *sql.Tx, problem Problem, now time.Time) error {
+	_, err := tx.Exec(`
+		UPDATE problems SET
+			resource_name = ?, resource_type = ?, region = ?, detector = ?,
+			risk_level = ?, lifecycle_state = ?, last_seen_at = ?
+		WHERE id = ?
+	`, problem.ResourceName, problem.ResourceType, problem.Region, problem.Detector, problem.RiskLevel, problem.LifecycleState, now, problem.ID)
+	return err
+}
+
+func closeProblem(tx *sql.Tx, id string, now time.Time) error {
+	_, err := tx.Exec(`UPDATE problems SET is_open = 0, closed_at = ? WHERE id = ?`, now, id)
+	return err
+}
+
+func insertRiskTransition(tx *sql.Tx, id, fromRisk, toRisk string, now time.Time) error {
+	_, err := tx.Exec(`
+		INSERT INTO risk_transitions (problem_id, from_risk, to_risk, changed_at)
+		VALUES (?, ?, ?, ?)
+	`, id, fromRisk, toRisk, now)
+	return err
+}
@@ -0,0 +1,136 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	st, err := Open(filepath.Join(t.TempDir(), "cloudguard.db"))
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+func TestRecordAndDiffNewProblem(t *testing.T) {
+	st := openTestStore(t)
+
+	report, err := st.RecordAndDiff([]Problem{
+		{ID: "p1", ResourceName: "bucket-1", ResourceType: "ObjectStorage", Region: "us-ashburn-1", Detector: "ConfigurationDetector", RiskLevel: "HIGH"},
+	}, 30)
+	if err != nil {
+		t.Fatalf("RecordAndDiff() error: %v", err)
+	}
+
+	if len(report.New) != 1 || report.New[0].ID != "p1" {
+		t.Errorf("New = %+v, want a single entry for p1", report.New)
+	}
+	if len(report.Resolved) != 0 || len(report.Escalated) != 0 {
+		t.Errorf("expected no resolved/escalated on first sighting, got resolved=%+v escalated=%+v", report.Resolved, report.Escalated)
+	}
+}
+
+func TestRecordAndDiffResolvedKeepsDescriptiveFields(t *testing.T) {
+	st := openTestStore(t)
+
+	if _, err := st.RecordAndDiff([]Problem{
+		{ID: "p1", ResourceName: "bucket-1", ResourceType: "ObjectStorage", Region: "us-ashburn-1", Detector: "ConfigurationDetector", RiskLevel: "HIGH"},
+	}, 30); err != nil {
+		t.Fatalf("first RecordAndDiff() error: %v", err)
+	}
+
+	// Second scan no longer sees p1: it should be reported resolved, with
+	// its descriptive fields populated from the last time it was seen.
+	report, err := st.RecordAndDiff(nil, 30)
+	if err != nil {
+		t.Fatalf("second RecordAndDiff() error: %v", err)
+	}
+
+	if len(report.Resolved) != 1 {
+		t.Fatalf("Resolved = %+v, want exactly one entry", report.Resolved)
+	}
+
+	resolved := report.Resolved[0]
+	if resolved.ID != "p1" {
+		t.Errorf("Resolved[0].ID = %q, want p1", resolved.ID)
+	}
+	if resolved.ResourceName != "bucket-1" {
+		t.Errorf("Resolved[0].ResourceName = %q, want bucket-1", resolved.ResourceName)
+	}
+	if resolved.ResourceType != "ObjectStorage" {
+		t.Errorf("Resolved[0].ResourceType = %q, want ObjectStorage", resolved.ResourceType)
+	}
+	if resolved.Region != "us-ashburn-1" {
+		t.Errorf("Resolved[0].Region = %q, want us-ashburn-1", resolved.Region)
+	}
+	if resolved.Detector != "ConfigurationDetector" {
+		t.Errorf("Resolved[0].Detector = %q, want ConfigurationDetector", resolved.Detector)
+	}
+	if resolved.RiskLevel != "HIGH" {
+		t.Errorf("Resolved[0].RiskLevel = %q, want HIGH", resolved.RiskLevel)
+	}
+}
+
+func TestRecordAndDiffEscalation(t *testing.T) {
+	st := openTestStore(t)
+
+	if _, err := st.RecordAndDiff([]Problem{
+		{ID: "p1", RiskLevel: "MEDIUM"},
+	}, 30); err != nil {
+		t.Fatalf("first RecordAndDiff() error: %v", err)
+	}
+
+	report, err := st.RecordAndDiff([]Problem{
+		{ID: "p1", RiskLevel: "CRITICAL"},
+	}, 30)
+	if err != nil {
+		t.Fatalf("second RecordAndDiff() error: %v", err)
+	}
+
+	if len(report.Escalated) != 1 {
+		t.Fatalf("Escalated = %+v, want exactly one entry", report.Escalated)
+	}
+	if report.Escalated[0].From != "MEDIUM" || report.Escalated[0].To != "CRITICAL" {
+		t.Errorf("Escalated[0] = %+v, want From=MEDIUM To=CRITICAL", report.Escalated[0])
+	}
+}
+
+func TestRecordAndDiffDowngradeIsNotEscalation(t *testing.T) {
+	st := openTestStore(t)
+
+	if _, err := st.RecordAndDiff([]Problem{
+		{ID: "p1", RiskLevel: "CRITICAL"},
+	}, 30); err != nil {
+		t.Fatalf("first RecordAndDiff() error: %v", err)
+	}
+
+	report, err := st.RecordAndDiff([]Problem{
+		{ID: "p1", RiskLevel: "LOW"},
+	}, 30)
+	if err != nil {
+		t.Fatalf("second RecordAndDiff() error: %v", err)
+	}
+
+	if len(report.Escalated) != 0 {
+		t.Errorf("Escalated = %+v, want none for a risk-level downgrade", report.Escalated)
+	}
+}
+
+func TestRecordAndDiffLongStanding(t *testing.T) {
+	st := openTestStore(t)
+
+	report, err := st.RecordAndDiff([]Problem{
+		{ID: "p1", DaysSinceDetection: 45},
+		{ID: "p2", DaysSinceDetection: 5},
+	}, 30)
+	if err != nil {
+		t.Fatalf("RecordAndDiff() error: %v", err)
+	}
+
+	if len(report.LongStanding) != 1 || report.LongStanding[0].ID != "p1" {
+		t.Errorf("LongStanding = %+v, want exactly p1", report.LongStanding)
+	}
+}